@@ -2,23 +2,17 @@
 // managed by the Cluster.
 package adder
 
-// #cgo LDFLAGS: -L../pdp -lpdp -lssl -lcrypto
-// extern int pdp_tag_file(char *filepath, size_t filepath_len, char *tagfilepath, size_t tagfilepath_len,char* keypath,char* password);
-import "C"
-
 import (
-	"bufio"
 	"context"
 	"fmt"
 	"io"
 	"mime/multipart"
 	"os"
-	"os/user"
-	"path/filepath"
 	"strings"
 
 	"github.com/kebohan1/ipfs-cluster/adder/ipfsadd"
 	"github.com/kebohan1/ipfs-cluster/api"
+	pdpconfig "github.com/kebohan1/ipfs-cluster/config/pdp"
 
 	cid "github.com/ipfs/go-cid"
 	files "github.com/ipfs/go-ipfs-files"
@@ -35,9 +29,10 @@ var logger = logging.Logger("adder")
 // add implementation.
 type ClusterDAGService interface {
 	ipld.DAGService
-	// Finalize receives the IPFS content root CID as
-	// returned by the ipfs adder.
-	Finalize(ctx context.Context, ipfsRoot cid.Cid) (cid.Cid, error)
+	// Finalize receives the IPFS content root CID as returned by the
+	// ipfs adder, along with the CID of the PDP tag file DAG for that
+	// content (cid.Undef if the content was not tagged), and pins both.
+	Finalize(ctx context.Context, ipfsRoot cid.Cid, tagRoot cid.Cid) (cid.Cid, error)
 }
 
 // Adder is used to add content to IPFS Cluster using an implementation of
@@ -55,13 +50,18 @@ type Adder struct {
 	// about the block, the CID, the Name etc. and are mostly
 	// meant to be streamed back to the user.
 	output chan *api.AddedOutput
+
+	// tagger produces PDP tags for added files. It defaults to
+	// NoopTagger, so PDP auditing is opt-in.
+	tagger PDPTagger
 }
 
-// New returns a new Adder with the given ClusterDAGService, add options and a
-// channel to send updates during the adding process.
+// New returns a new Adder with the given ClusterDAGService, add options, a
+// channel to send updates during the adding process, and the PDP
+// configuration to tag added files with (nil disables PDP tagging).
 //
 // An Adder may only be used once.
-func New(ds ClusterDAGService, p *api.AddParams, out chan *api.AddedOutput) *Adder {
+func New(ds ClusterDAGService, p *api.AddParams, out chan *api.AddedOutput, pdpCfg *pdpconfig.Config) *Adder {
 	// Discard all progress update output as the caller has not provided
 	// a channel for them to listen on.
 	if out == nil {
@@ -76,7 +76,76 @@ func New(ds ClusterDAGService, p *api.AddParams, out chan *api.AddedOutput) *Add
 		dgs:    ds,
 		params: p,
 		output: out,
+		tagger: NewPDPTagger(pdpCfg),
+	}
+}
+
+// SetPDPTagger sets the PDPTagger used to tag files as they are added.
+// Callers that need PDP auditing should call this before FromFiles/FromFile
+// with a CGOTagger, a pure-Go implementation, or one backed by a remote
+// tagging service. If unset, Adder uses the tagger that New derived from
+// its PDP configuration (NoopTagger when that configuration is nil).
+func (a *Adder) SetPDPTagger(t PDPTagger) {
+	a.tagger = t
+}
+
+// bufferForTagging copies r into a temporary file and rewinds it, so that
+// both PDP tagging and the subsequent content add can each read the full
+// stream. Without this, a PDPTagger implementation that actually reads
+// from r (e.g. a pure-Go tagger, or one backed by a remote tagging
+// service) would consume r itself, leaving nothing -- or a truncated
+// tail -- for ipfsAdder.AddAllAndPin to add, since r (e.g. an HTTP
+// upload body) is often not seekable and cannot simply be re-read.
+// Callers must arrange for the returned file to be closed and removed
+// via removeTempFile once they are done with it.
+func bufferForTagging(r io.Reader) (*os.File, error) {
+	tmp, err := os.CreateTemp("", "ipfs-cluster-pdp-")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := io.Copy(tmp, r); err != nil {
+		removeTempFile(tmp)
+		return nil, err
+	}
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		removeTempFile(tmp)
+		return nil, err
+	}
+	return tmp, nil
+}
+
+// removeTempFile closes f and removes it from disk, ignoring errors from
+// the removal (the file may already be gone, e.g. under os.TempDir()
+// cleanup).
+func removeTempFile(f *os.File) {
+	name := f.Name()
+	f.Close()
+	os.Remove(name)
+}
+
+// addTagFile reads the PDP tag file at path and adds it to the cluster DAG
+// as its own root, using the same ClusterDAGService (and therefore the same
+// block allocations) as the data being tagged. It returns the resulting
+// tag root CID, so that a cluster peer holding a replica of the data can
+// later fetch the tag to run a PDP challenge against it.
+func (a *Adder) addTagFile(ctx context.Context, path string) (cid.Cid, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return cid.Undef, err
+	}
+	defer f.Close()
+
+	tagAdder, err := ipfsadd.NewAdder(ctx, a.dgs)
+	if err != nil {
+		return cid.Undef, err
+	}
+	tagAdder.Out = a.output
+
+	tagNode, err := tagAdder.AddAllAndPin(files.NewReaderFile(f))
+	if err != nil {
+		return cid.Undef, err
 	}
+	return tagNode.Cid(), nil
 }
 
 func (a *Adder) setContext(ctx context.Context) {
@@ -164,28 +233,8 @@ func (a *Adder) FromFiles(ctx context.Context, f files.Directory) (cid.Cid, erro
 			[]files.DirEntry{files.FileEntry("", f)},
 		)
 	}
-	usr, _ := user.Current()
-	absPath, err := filepath.Abs(usr.HomeDir)
-	pdpPath := filepath.Join(absPath, "/.ipfs-cluster/passphrase")
-	pdpkeyPath := filepath.Join(absPath, "/.ipfs-cluster/key/")
-	pdptagPath := filepath.Join(absPath, "/.ipfs-cluster/tag/")
-
-	logger.Infof("pdpKeyPath:%s", pdpkeyPath)
-	logger.Infof("pdpTagPath:%s", pdptagPath)
-
-	passwordFile, _ := os.Open(pdpPath)
-	defer passwordFile.Close()
-	var password string
-	scanner := bufio.NewScanner(passwordFile)
-	scanner.Split(bufio.ScanLines)
-	for scanner.Scan() {
-		password = scanner.Text()
-	}
-
-	logger.Infof("pdpPassword:%s", password)
-
 	it := f.Entries()
-	var adderRoot ipld.Node
+	var clusterRoot cid.Cid
 	for it.Next() {
 		// In order to set the AddedOutput names right, we use
 		// OutputPrefix:
@@ -203,36 +252,102 @@ func (a *Adder) FromFiles(ctx context.Context, f files.Directory) (cid.Cid, erro
 		// events before sending to user).
 		ipfsAdder.OutputPrefix = it.Name()
 		name := it.Name()
-		size, err := it.Node().Size()
-		err_tag := C.pdp_tag_file(C.CString(name), C.ulong(size), C.CString(pdptagPath), C.ulong(len(pdptagPath)), C.CString(pdpkeyPath), C.CString(password))
-		if err_tag == 1 {
-			logger.Debugf("PDP process Error: %s", it.Name())
-			return cid.Undef, a.ctx.Err()
+
+		root, err := a.addEntry(ipfsAdder, name, it.Node())
+		if err != nil {
+			return cid.Undef, err
 		}
-		select {
-		case <-a.ctx.Done():
-			return cid.Undef, a.ctx.Err()
-		default:
-			logger.Debugf("ipfsAdder AddFile(%s)", it.Name())
+		clusterRoot = root
+	}
+	if it.Err() != nil {
+		return cid.Undef, it.Err()
+	}
+
+	return clusterRoot, nil
+}
 
-			adderRoot, err = ipfsAdder.AddAllAndPin(it.Node())
+// addEntry tags (if a.tagger is configured to), adds and finalizes a
+// single top-level entry of a directory or multipart add, returning its
+// cluster root CID. It is factored out of the FromFiles loop so that the
+// temp file used to buffer an entry for tagging is removed (via its
+// defer) at the end of that entry's own call, instead of accumulating
+// open, undeleted temp files under os.TempDir() until the whole
+// directory add returns.
+func (a *Adder) addEntry(ipfsAdder *ipfsadd.Adder, name string, node files.Node) (cid.Cid, error) {
+	nodeToAdd := node
+	var tagInfo TagInfo
+	// Buffering an entry to disk so it can be both tagged and added
+	// costs a full extra copy of its content, and a.tagger.TagFile
+	// otherwise doesn't need to read it at all (NoopTagger, the
+	// default, never does). Skip that cost entirely when the
+	// configured tagger is disabled, so builds/deployments that don't
+	// use PDP auditing see no behavior change for ordinary adds.
+	if a.tagger.Enabled() {
+		if entryFile := files.ToFile(node); entryFile != nil {
+			size, err := entryFile.Size()
 			if err != nil {
-				logger.Error("error adding to cluster: ", err)
 				return cid.Undef, err
 			}
+
+			buffered, err := bufferForTagging(entryFile)
+			if err != nil {
+				return cid.Undef, err
+			}
+			defer removeTempFile(buffered)
+
+			tagInfo, err = a.tagger.TagFile(a.ctx, name, size, buffered)
+			if err != nil {
+				logger.Debugf("PDP process Error: %s: %s", name, err)
+				return cid.Undef, err
+			}
+			if _, err := buffered.Seek(0, io.SeekStart); err != nil {
+				return cid.Undef, err
+			}
+			nodeToAdd = files.NewReaderFile(buffered)
 		}
 	}
-	if it.Err() != nil {
-		return cid.Undef, it.Err()
+
+	var adderRoot ipld.Node
+	select {
+	case <-a.ctx.Done():
+		return cid.Undef, a.ctx.Err()
+	default:
+		logger.Debugf("ipfsAdder AddFile(%s)", name)
+
+		var err error
+		adderRoot, err = ipfsAdder.AddAllAndPin(nodeToAdd)
+		if err != nil {
+			logger.Error("error adding to cluster: ", err)
+			return cid.Undef, err
+		}
+	}
+
+	// Each entry is tagged, added and finalized (pinned) on its own:
+	// with a directory of more than one top-level entry, finalizing
+	// once after the loop (using only the last entry's values) would
+	// leave every earlier entry's tag DAG pinned on its allocated
+	// peers but never cluster-pinned, making it immediately
+	// GC-eligible and its CID unrecoverable.
+	tagRoot := cid.Undef
+	if tagInfo.TagPath != "" {
+		var err error
+		tagRoot, err = a.addTagFile(a.ctx, tagInfo.TagPath)
+		if err != nil {
+			logger.Error("error adding PDP tag to cluster: ", err)
+			return cid.Undef, err
+		}
 	}
 
-	clusterRoot, err := a.dgs.Finalize(a.ctx, adderRoot.Cid())
+	root, err := a.dgs.Finalize(a.ctx, adderRoot.Cid(), tagRoot)
 	if err != nil {
 		logger.Error("error finalizing adder:", err)
 		return cid.Undef, err
 	}
-	logger.Infof("%s successfully added to cluster", clusterRoot)
-	return clusterRoot, nil
+	if tagRoot != cid.Undef {
+		a.output <- &api.AddedOutput{Name: name + ".pdptag", Cid: tagRoot}
+	}
+	logger.Infof("%s successfully added to cluster", root)
+	return root, nil
 }
 
 // FromFile adds content file. The adder will no longer
@@ -275,47 +390,5 @@ func (a *Adder) FromFile(ctx context.Context, reader io.Reader, name string) (ci
 	prefix.MhLength = -1
 	ipfsAdder.CidBuilder = &prefix
 
-	file := files.NewReaderFile(reader)
-	usr, _ := user.Current()
-	absPath, err := filepath.Abs(usr.HomeDir)
-	pdpPath := filepath.Join(absPath, "/.ipfs-cluster/passphrase")
-	pdpkeyPath := filepath.Join(absPath, "/.ipfs-cluster/key/")
-	pdptagPath := filepath.Join(absPath, "/.ipfs-cluster/tag/")
-
-	logger.Infof("pdpKeyPath:%s", pdpkeyPath)
-	logger.Infof("pdpTagPath:%s", pdptagPath)
-
-	passwordFile, _ := os.Open(pdpPath)
-	defer passwordFile.Close()
-	var password string
-	scanner := bufio.NewScanner(passwordFile)
-	scanner.Split(bufio.ScanLines)
-	for scanner.Scan() {
-		password = scanner.Text()
-	}
-
-	logger.Infof("pdpPassword:%s", password)
-
-	size, err := file.Size()
-	err_tag := C.pdp_tag_file(C.CString(name), C.ulong(size), C.CString(pdptagPath), C.ulong(len(pdptagPath)), C.CString(pdpkeyPath), C.CString(password))
-	if err_tag == 1 {
-		logger.Debugf("PDP process Error: %s", name)
-		return cid.Undef, a.ctx.Err()
-	}
-	logger.Debugf("ipfsAdder AddFile(%s)", name)
-	var adderRoot ipld.Node
-	adderRoot, err = ipfsAdder.AddAllAndPin(file)
-	if err != nil {
-		logger.Error("error adding to cluster: ", err)
-		return cid.Undef, err
-
-	}
-
-	clusterRoot, err := a.dgs.Finalize(a.ctx, adderRoot.Cid())
-	if err != nil {
-		logger.Error("error finalizing adder:", err)
-		return cid.Undef, err
-	}
-	logger.Infof("%s successfully added to cluster", clusterRoot)
-	return clusterRoot, nil
+	return a.addEntry(ipfsAdder, name, files.NewReaderFile(reader))
 }
@@ -0,0 +1,64 @@
+package adder
+
+import (
+	"context"
+	"io"
+)
+
+// TagInfo describes the result of tagging a file for later PDP
+// (Provable Data Possession) auditing.
+type TagInfo struct {
+	// Name is the name of the file the tag was generated for.
+	Name string
+	// TagPath is the location of the generated tag file.
+	TagPath string
+	// KeyPath is the location of the key used to produce the tag.
+	KeyPath string
+}
+
+// PDPTagger is implemented by types that can produce a PDP tag for a file
+// before it is added to the cluster DAG. Implementations may wrap a
+// cgo-backed PDP library (see CGOTagger), compute tags in pure Go, or
+// delegate to a remote tagging service reached through the rpc client.
+//
+// Adder uses a PDPTagger to replace the hardcoded pdp_tag_file cgo call
+// that used to live in FromFiles/FromFile, so that cluster can be built
+// and tested without libpdp/libssl.
+type PDPTagger interface {
+	// TagFile tags the content of reader, which has the given name and
+	// size, and returns information about the resulting tag file.
+	TagFile(ctx context.Context, name string, size int64, reader io.Reader) (TagInfo, error)
+
+	// KeyPath returns the directory holding the PDP keys used by this
+	// tagger.
+	KeyPath() string
+
+	// TagPath returns the directory where tag files are written.
+	TagPath() string
+
+	// Enabled reports whether this tagger actually tags files. Callers
+	// use it to skip the extra buffering TagFile's reader requires (see
+	// adder.bufferForTagging) when PDP auditing is off, so that the
+	// default, cgo-less build pays no extra cost for ordinary adds.
+	Enabled() bool
+}
+
+// NoopTagger is a PDPTagger that performs no tagging. It is the tagger an
+// Adder uses when New is given a nil PDP configuration, or in builds
+// without cgo, and no other PDPTagger has been set, which keeps cluster
+// usable in builds without cgo or when PDP auditing is disabled.
+type NoopTagger struct{}
+
+// TagFile returns a zero TagInfo without doing any work.
+func (NoopTagger) TagFile(ctx context.Context, name string, size int64, reader io.Reader) (TagInfo, error) {
+	return TagInfo{}, nil
+}
+
+// KeyPath returns the empty string, as NoopTagger uses no keys.
+func (NoopTagger) KeyPath() string { return "" }
+
+// TagPath returns the empty string, as NoopTagger writes no tag files.
+func (NoopTagger) TagPath() string { return "" }
+
+// Enabled always returns false, as NoopTagger never tags anything.
+func (NoopTagger) Enabled() bool { return false }
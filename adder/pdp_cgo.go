@@ -0,0 +1,87 @@
+//go:build cgo
+// +build cgo
+
+package adder
+
+// #cgo LDFLAGS: -L../pdp -lpdp -lssl -lcrypto
+// extern int pdp_tag_file(char *filepath, size_t filepath_len, char *tagfilepath, size_t tagfilepath_len, char *keypath, char *password);
+import "C"
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+
+	pdpconfig "github.com/kebohan1/ipfs-cluster/config/pdp"
+)
+
+// CGOTagger is a PDPTagger backed by the native libpdp/libssl
+// implementation via cgo. It requires cluster to be built with
+// CGO_ENABLED=1 and linked against libpdp.
+type CGOTagger struct {
+	keyPath    string
+	tagPath    string
+	passphrase func() (string, error)
+}
+
+// NewCGOTagger returns a CGOTagger that writes tag files under tagPath
+// using the keys in keyPath. passphrase is called lazily to obtain the
+// PDP passphrase; callers normally pass (*pdpconfig.Config).Passphrase,
+// which resolves it once (from an env var, a file, or a keyring) and
+// caches it, instead of re-reading it from disk on every add request.
+func NewCGOTagger(keyPath, tagPath string, passphrase func() (string, error)) *CGOTagger {
+	return &CGOTagger{
+		keyPath:    keyPath,
+		tagPath:    tagPath,
+		passphrase: passphrase,
+	}
+}
+
+// NewPDPTagger returns the PDPTagger to use for cfg: a CGOTagger wired to
+// resolve its passphrase from cfg, or NoopTagger if cfg is nil.
+func NewPDPTagger(cfg *pdpconfig.Config) PDPTagger {
+	if cfg == nil {
+		return NoopTagger{}
+	}
+	return NewCGOTagger(cfg.KeyPath, cfg.TagPath, cfg.Passphrase)
+}
+
+// KeyPath returns the directory holding the PDP keys.
+func (t *CGOTagger) KeyPath() string { return t.keyPath }
+
+// TagPath returns the directory where tag files are written.
+func (t *CGOTagger) TagPath() string { return t.tagPath }
+
+// Enabled always returns true, as CGOTagger actually tags every file it
+// is given.
+func (t *CGOTagger) Enabled() bool { return true }
+
+// TagFile calls the native pdp_tag_file routine on the file at path name,
+// writing the resulting tag file under t.TagPath(). It does not read from
+// reader: what gets tagged is whatever is stored at name on the local
+// filesystem.
+func (t *CGOTagger) TagFile(ctx context.Context, name string, size int64, reader io.Reader) (TagInfo, error) {
+	password, err := t.passphrase()
+	if err != nil {
+		return TagInfo{}, err
+	}
+
+	errTag := C.pdp_tag_file(
+		C.CString(name),
+		C.ulong(size),
+		C.CString(t.tagPath),
+		C.ulong(len(t.tagPath)),
+		C.CString(t.keyPath),
+		C.CString(password),
+	)
+	if errTag == 1 {
+		return TagInfo{}, fmt.Errorf("PDP tagging failed for %s", name)
+	}
+
+	return TagInfo{
+		Name:    name,
+		TagPath: filepath.Join(t.tagPath, name),
+		KeyPath: t.keyPath,
+	}, nil
+}
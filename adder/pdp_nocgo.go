@@ -0,0 +1,18 @@
+//go:build !cgo
+// +build !cgo
+
+package adder
+
+import (
+	pdpconfig "github.com/kebohan1/ipfs-cluster/config/pdp"
+)
+
+// NewPDPTagger returns NoopTagger, as CGOTagger is unavailable in builds
+// without cgo. It is provided so that callers can wire a pdpconfig.Config
+// into Adder the same way regardless of build tags.
+func NewPDPTagger(cfg *pdpconfig.Config) PDPTagger {
+	if cfg != nil {
+		logger.Warning("PDP auditing requested but this binary was built without cgo; tagging is disabled")
+	}
+	return NoopTagger{}
+}
@@ -65,13 +65,28 @@ func (dgs *DAGService) Add(ctx context.Context, node ipld.Node) error {
 	return dgs.ba.Add(ctx, node)
 }
 
-// Finalize pins the last Cid added to this DAGService.
-func (dgs *DAGService) Finalize(ctx context.Context, root cid.Cid) (cid.Cid, error) {
-	// Cluster pin the result
-	rootPin := api.PinWithOpts(root, dgs.pinOpts)
-	rootPin.Allocations = dgs.dests
+// Finalize pins the last Cid added to this DAGService. If tagRoot is not
+// cid.Undef, it is the root of a PDP tag file DAG produced for root: it is
+// pinned with the same allocations as root, and recorded on root's Pin so
+// that any peer holding a replica of root can later fetch the tag to run a
+// PDP challenge against it. The tag is pinned first, so that root is never
+// recorded as tagged without its tag actually being pinned.
+func (dgs *DAGService) Finalize(ctx context.Context, root cid.Cid, tagRoot cid.Cid) (cid.Cid, error) {
+	dests := dgs.dests
 	dgs.dests = nil
 
+	rootPin := api.PinWithOpts(root, dgs.pinOpts)
+	rootPin.Allocations = dests
+
+	if tagRoot != cid.Undef {
+		tagPin := api.PinWithOpts(tagRoot, dgs.pinOpts)
+		tagPin.Allocations = dests
+		if err := adder.Pin(ctx, dgs.rpcClient, tagPin); err != nil {
+			return root, err
+		}
+		rootPin.PDPTagCid = tagRoot
+	}
+
 	return root, adder.Pin(ctx, dgs.rpcClient, rootPin)
 }
 
@@ -0,0 +1,46 @@
+package api
+
+import (
+	cid "github.com/ipfs/go-cid"
+)
+
+// AddParams carries the options that control how content is chunked,
+// hashed and laid out while being added.
+type AddParams struct {
+	PinOptions
+
+	// Layout is either "trickle" or "balanced" (the default).
+	Layout string
+	// Chunker is the chunking algorithm and its parameters, e.g.
+	// "size-262144" or "rabin-min-avg-max".
+	Chunker string
+	// RawLeaves disables wrapping leaf nodes in a unixfs protobuf.
+	RawLeaves bool
+	// NoCopy adds content using filestore, without copying it into the
+	// ipfs repo.
+	NoCopy bool
+	// Progress, if true, requests progress updates as AddedOutput
+	// events while adding.
+	Progress bool
+	// Wrap wraps the added content in a directory.
+	Wrap bool
+	// CidVersion is the CID version used for the generated DAG.
+	CidVersion int
+	// HashFun is the name of the hash function used for the generated
+	// DAG, e.g. "sha2-256".
+	HashFun string
+}
+
+// AddedOutput is streamed back to the caller of Adder.FromFiles/FromFile
+// as content is added, so that progress (and, for PDP-tagged content,
+// the resulting tag CID) can be reported incrementally.
+type AddedOutput struct {
+	// Name is the path or name of the entry this update is about.
+	Name string
+	// Cid is the CID produced for this entry.
+	Cid cid.Cid
+	// Bytes is the number of bytes processed so far for this entry.
+	Bytes uint64
+	// Size is the total size of this entry, if known.
+	Size string
+}
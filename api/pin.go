@@ -0,0 +1,66 @@
+// Package api holds the types shared between ipfs-cluster components and
+// its clients: pin and add request/response objects, and the options
+// that configure them.
+package api
+
+import (
+	cid "github.com/ipfs/go-cid"
+	peer "github.com/libp2p/go-libp2p-core/peer"
+)
+
+// PinMode is used to express how a Pin should be pinned, either
+// recursively or directly.
+type PinMode int
+
+const (
+	// PinModeRecursive pins a CID and all its children.
+	PinModeRecursive PinMode = iota
+	// PinModeDirect pins only a CID, without its children.
+	PinModeDirect
+)
+
+// PinOptions carries the pinning preferences attached to an add request
+// or an explicit pin request.
+type PinOptions struct {
+	// Name is a human-readable label for the pin.
+	Name string
+	// Mode is whether the pin is recursive or direct.
+	Mode PinMode
+	// ReplicationFactorMin is the minimum number of peers that should
+	// hold a replica of the pinned content. -1 means "all peers".
+	ReplicationFactorMin int
+	// ReplicationFactorMax is the maximum number of peers that should
+	// hold a replica of the pinned content. -1 means "all peers".
+	ReplicationFactorMax int
+	// UserAllocations, if set, pins the content on exactly these
+	// peers, bypassing the usual allocator.
+	UserAllocations []peer.ID
+}
+
+// Pin tracks a CID pinned by the cluster: its allocations, pinning
+// options, and any related CIDs (such as a PDP tag file) that ride
+// alongside it.
+type Pin struct {
+	PinOptions
+
+	// Cid is the content identifier being pinned.
+	Cid cid.Cid
+
+	// Allocations is the set of peers holding (or expected to hold) a
+	// replica of Cid.
+	Allocations []peer.ID
+
+	// PDPTagCid, if set, is the root of the PDP tag file DAG produced
+	// for Cid by adder.PDPTagger. It lets any peer holding a replica of
+	// Cid fetch the tag and run a PDP challenge against it, without
+	// needing to contact the node that originally added the content.
+	PDPTagCid cid.Cid
+}
+
+// PinWithOpts returns a Pin for c configured with opts.
+func PinWithOpts(c cid.Cid, opts PinOptions) *Pin {
+	return &Pin{
+		PinOptions: opts,
+		Cid:        c,
+	}
+}
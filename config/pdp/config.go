@@ -0,0 +1,197 @@
+// Package pdp holds the cluster configuration for PDP (Provable Data
+// Possession) tagging: where keys and tag files live, and how to obtain
+// the passphrase protecting the key. It is loaded once as part of the
+// cluster's main configuration at startup, instead of being read from
+// disk (and logged in full) on every add request.
+package pdp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// ConfigKey is this component's key in the cluster's main configuration
+// file.
+const ConfigKey = "pdp"
+
+const (
+	envPassphrase = "CLUSTER_PDP_PASSPHRASE"
+
+	defaultKeyPath = "~/.ipfs-cluster/key"
+	defaultTagPath = "~/.ipfs-cluster/tag"
+)
+
+// KeyringLookup resolves the PDP passphrase from the operator's keyring.
+// It is a variable rather than a fixed implementation because cluster
+// does not vendor a specific keyring library; deployments that want
+// Config.Keyring support should set this at init time.
+var KeyringLookup = func() (string, error) {
+	return "", errors.New("pdp: no keyring backend configured (set pdp.KeyringLookup)")
+}
+
+// jsonConfig is the on-disk representation of Config.
+type jsonConfig struct {
+	KeyPath          string `json:"key_path"`
+	TagPath          string `json:"tag_path"`
+	PassphraseEnvVar string `json:"passphrase_env_var,omitempty"`
+	PassphraseFile   string `json:"passphrase_file,omitempty"`
+	Keyring          bool   `json:"keyring,omitempty"`
+}
+
+// Config holds the PDP tagging settings for a cluster peer.
+type Config struct {
+	// KeyPath is the directory holding the PDP keys.
+	KeyPath string
+	// TagPath is the directory where tag files are written.
+	TagPath string
+
+	// PassphraseEnvVar, if set, names an environment variable holding
+	// the passphrase. Checked before PassphraseFile and Keyring.
+	PassphraseEnvVar string
+	// PassphraseFile is a file holding the passphrase, read once, on the
+	// first call to Passphrase, if PassphraseEnvVar is unset or empty.
+	PassphraseFile string
+	// Keyring, if true, resolves the passphrase via KeyringLookup. It is
+	// only used when neither PassphraseEnvVar nor PassphraseFile is set.
+	Keyring bool
+
+	passphraseOnce sync.Once
+	passphrase     string
+	passphraseErr  error
+}
+
+// ConfigKey returns this component's key in the cluster configuration.
+func (cfg *Config) ConfigKey() string {
+	return ConfigKey
+}
+
+// Default sets KeyPath and TagPath to ~/.ipfs-cluster/{key,tag} and
+// configures the passphrase to come from the CLUSTER_PDP_PASSPHRASE
+// environment variable.
+func (cfg *Config) Default() error {
+	cfg.KeyPath = expandHome(defaultKeyPath)
+	cfg.TagPath = expandHome(defaultTagPath)
+	cfg.PassphraseEnvVar = envPassphrase
+	cfg.PassphraseFile = ""
+	cfg.Keyring = false
+	return nil
+}
+
+// Validate returns an error if cfg is not usable.
+func (cfg *Config) Validate() error {
+	if cfg.KeyPath == "" {
+		return errors.New("pdp.key_path is unset")
+	}
+	if cfg.TagPath == "" {
+		return errors.New("pdp.tag_path is unset")
+	}
+
+	sources := 0
+	if cfg.PassphraseEnvVar != "" {
+		sources++
+	}
+	if cfg.PassphraseFile != "" {
+		sources++
+	}
+	if cfg.Keyring {
+		sources++
+	}
+	if sources == 0 {
+		return errors.New("pdp: no passphrase source configured (env var, file or keyring)")
+	}
+	return nil
+}
+
+// LoadJSON parses raw as a PDP configuration, applies it to cfg, expanding
+// any leading "~" in KeyPath/TagPath/PassphraseFile, and validates it.
+func (cfg *Config) LoadJSON(raw []byte) error {
+	jcfg := &jsonConfig{}
+	if err := json.Unmarshal(raw, jcfg); err != nil {
+		return fmt.Errorf("error unmarshaling pdp config: %s", err)
+	}
+
+	cfg.KeyPath = expandHome(jcfg.KeyPath)
+	cfg.TagPath = expandHome(jcfg.TagPath)
+	cfg.PassphraseEnvVar = jcfg.PassphraseEnvVar
+	cfg.PassphraseFile = expandHome(jcfg.PassphraseFile)
+	cfg.Keyring = jcfg.Keyring
+
+	return cfg.Validate()
+}
+
+// ToJSON returns the JSON representation of cfg. The resolved passphrase
+// itself is never serialized, only the configured source.
+func (cfg *Config) ToJSON() ([]byte, error) {
+	return json.MarshalIndent(&jsonConfig{
+		KeyPath:          cfg.KeyPath,
+		TagPath:          cfg.TagPath,
+		PassphraseEnvVar: cfg.PassphraseEnvVar,
+		PassphraseFile:   cfg.PassphraseFile,
+		Keyring:          cfg.Keyring,
+	}, "", "    ")
+}
+
+// String returns a redacted representation of cfg, safe to log: the
+// resolved passphrase, if any, is never included.
+func (cfg *Config) String() string {
+	return fmt.Sprintf(
+		"pdp.Config{KeyPath: %q, TagPath: %q, PassphraseEnvVar: %q, PassphraseFile: %q, Keyring: %v, Passphrase: <redacted>}",
+		cfg.KeyPath, cfg.TagPath, cfg.PassphraseEnvVar, cfg.PassphraseFile, cfg.Keyring,
+	)
+}
+
+// Passphrase resolves and caches the PDP passphrase from the configured
+// source (env var, file, or keyring, in that order of precedence). It is
+// read once per Config, so that callers (like adder.CGOTagger) no longer
+// need to open the passphrase file on every add request. A single Config
+// is shared by every concurrent Adder/CGOTagger once loaded at startup,
+// so resolution happens inside a sync.Once. Callers must never log the
+// returned value.
+func (cfg *Config) Passphrase() (string, error) {
+	cfg.passphraseOnce.Do(func() {
+		cfg.passphrase, cfg.passphraseErr = cfg.resolvePassphrase()
+	})
+	return cfg.passphrase, cfg.passphraseErr
+}
+
+func (cfg *Config) resolvePassphrase() (string, error) {
+	switch {
+	case cfg.PassphraseEnvVar != "":
+		v := os.Getenv(cfg.PassphraseEnvVar)
+		if v == "" {
+			return "", fmt.Errorf("pdp: environment variable %s is unset", cfg.PassphraseEnvVar)
+		}
+		return v, nil
+	case cfg.PassphraseFile != "":
+		return readPassphraseFile(cfg.PassphraseFile)
+	case cfg.Keyring:
+		return KeyringLookup()
+	default:
+		return "", errors.New("pdp: no passphrase source configured")
+	}
+}
+
+func readPassphraseFile(path string) (string, error) {
+	data, err := os.ReadFile(expandHome(path))
+	if err != nil {
+		return "", fmt.Errorf("pdp: error reading passphrase file: %s", err)
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+func expandHome(path string) string {
+	if !strings.HasPrefix(path, "~") {
+		return path
+	}
+	usr, err := user.Current()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(usr.HomeDir, strings.TrimPrefix(path, "~"))
+}
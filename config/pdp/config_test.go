@@ -0,0 +1,83 @@
+package pdp
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestDefaultExpandsHome(t *testing.T) {
+	cfg := &Config{}
+	if err := cfg.Default(); err != nil {
+		t.Fatalf("Default() returned an error: %s", err)
+	}
+	if strings.HasPrefix(cfg.KeyPath, "~") {
+		t.Fatalf("expected KeyPath to have ~ expanded, got %q", cfg.KeyPath)
+	}
+	if strings.HasPrefix(cfg.TagPath, "~") {
+		t.Fatalf("expected TagPath to have ~ expanded, got %q", cfg.TagPath)
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Default() config does not validate: %s", err)
+	}
+}
+
+func TestValidatePassphraseSource(t *testing.T) {
+	cfg := &Config{KeyPath: "/key", TagPath: "/tag"}
+	if err := cfg.Validate(); err == nil {
+		t.Fatal("expected an error when no passphrase source is configured")
+	}
+
+	cfg.PassphraseEnvVar = envPassphrase
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("expected a configured env var to be a valid passphrase source: %s", err)
+	}
+}
+
+func TestLoadJSONExpandsHome(t *testing.T) {
+	cfg := &Config{}
+	raw := []byte(`{"key_path": "~/key", "tag_path": "~/tag", "passphrase_env_var": "` + envPassphrase + `"}`)
+	if err := cfg.LoadJSON(raw); err != nil {
+		t.Fatalf("LoadJSON returned an error: %s", err)
+	}
+	if strings.HasPrefix(cfg.KeyPath, "~") || strings.HasPrefix(cfg.TagPath, "~") {
+		t.Fatalf("expected ~ to be expanded, got KeyPath=%q TagPath=%q", cfg.KeyPath, cfg.TagPath)
+	}
+}
+
+func TestPassphrasePrecedence(t *testing.T) {
+	const envVar = "CLUSTER_PDP_PASSPHRASE_TEST"
+	os.Setenv(envVar, "s3cr3t")
+	defer os.Unsetenv(envVar)
+
+	// The env var takes precedence over the file and the keyring: the
+	// missing file and the unconfigured keyring are never consulted.
+	cfg := &Config{PassphraseEnvVar: envVar, PassphraseFile: "/nonexistent/passphrase", Keyring: true}
+	p, err := cfg.Passphrase()
+	if err != nil {
+		t.Fatalf("Passphrase() returned an error: %s", err)
+	}
+	if p != "s3cr3t" {
+		t.Fatalf("expected passphrase %q, got %q", "s3cr3t", p)
+	}
+
+	// The result is cached by sync.Once: changing the env var afterwards
+	// must not change the resolved passphrase.
+	os.Setenv(envVar, "different")
+	p, err = cfg.Passphrase()
+	if err != nil {
+		t.Fatalf("Passphrase() returned an error: %s", err)
+	}
+	if p != "s3cr3t" {
+		t.Fatalf("expected cached passphrase %q, got %q", "s3cr3t", p)
+	}
+}
+
+func TestExpandHome(t *testing.T) {
+	if got := expandHome("/absolute/path"); got != "/absolute/path" {
+		t.Fatalf("expected absolute paths to be unchanged, got %q", got)
+	}
+	if strings.HasPrefix(expandHome("~/foo"), "~") {
+		t.Fatal("expected ~ to be expanded")
+	}
+}
@@ -0,0 +1,21 @@
+package pdp
+
+import (
+	cid "github.com/ipfs/go-cid"
+)
+
+// Challenge is a single PDP challenge for root: the indices of the
+// blocks the challenged peer must include in its proof, verifiable
+// against the tag file at TagRoot.
+type Challenge struct {
+	Root    cid.Cid
+	TagRoot cid.Cid
+	Indices []int64
+}
+
+// Proof is a peer's response to a Challenge, computed from its locally
+// stored blocks and the tag file referenced by the challenge.
+type Proof struct {
+	Root  cid.Cid
+	Bytes []byte
+}
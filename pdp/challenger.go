@@ -0,0 +1,91 @@
+package pdp
+
+import (
+	"context"
+	"math/rand"
+
+	cid "github.com/ipfs/go-cid"
+	peer "github.com/libp2p/go-libp2p-core/peer"
+	rpc "github.com/libp2p/go-libp2p-gorpc"
+)
+
+// Challenger builds PDP challenges for pinned CIDs and dispatches them,
+// over the cluster's rpc client, to the peers that are supposed to hold a
+// replica.
+type Challenger struct {
+	rpcClient *rpc.Client
+	nSamples  int
+}
+
+// NewChallenger returns a Challenger that samples nSamples block indices
+// per challenge and sends them over rpcClient.
+func NewChallenger(rpcClient *rpc.Client, nSamples int) *Challenger {
+	return &Challenger{
+		rpcClient: rpcClient,
+		nSamples:  nSamples,
+	}
+}
+
+// Challenge samples nSamples indices out of numBlocks (0 if unknown) for
+// root/tagRoot and asks each of peers to prove, via the "PDP"/"Prove" rpc
+// endpoint, that it holds those blocks. Proofs from peers that fail to
+// respond are skipped rather than failing the whole challenge, so that a
+// single unreachable peer does not block auditing the others.
+//
+// If every peer's Prove call fails with ErrProvingNotImplemented,
+// Challenge returns ErrProvingNotImplemented alongside the (empty)
+// proofs, rather than looking identical to "every peer was
+// unreachable". Callers should treat that distinctly from a real
+// failure.
+func (c *Challenger) Challenge(ctx context.Context, root, tagRoot cid.Cid, numBlocks int, peers []peer.ID) ([]Proof, error) {
+	chal := Challenge{
+		Root:    root,
+		TagRoot: tagRoot,
+		Indices: c.sample(numBlocks),
+	}
+
+	proofs := make([]Proof, 0, len(peers))
+	allNotImplemented := len(peers) > 0
+	for _, p := range peers {
+		var proof Proof
+		err := c.rpcClient.CallContext(ctx, p, "PDP", "Prove", chal, &proof)
+		if err != nil {
+			if !isNotImplemented(err) {
+				allNotImplemented = false
+			}
+			logger.Errorf("error challenging %s for %s: %s", p, root, err)
+			continue
+		}
+		allNotImplemented = false
+		proofs = append(proofs, proof)
+	}
+	if allNotImplemented {
+		return proofs, ErrProvingNotImplemented
+	}
+	return proofs, nil
+}
+
+// isNotImplemented reports whether err is the rpc-transported form of
+// ErrProvingNotImplemented. gorpc returns remote errors as plain errors
+// carrying only the original message, so the sentinel can't survive the
+// round trip as a value errors.Is would recognize; comparing by message
+// is the best we can do here.
+func isNotImplemented(err error) bool {
+	return err != nil && err.Error() == ErrProvingNotImplemented.Error()
+}
+
+func (c *Challenger) sample(numBlocks int) []int64 {
+	n := c.nSamples
+	if numBlocks > 0 && n > numBlocks {
+		n = numBlocks
+	}
+	indices := make([]int64, n)
+	for i := range indices {
+		if numBlocks > 0 {
+			indices[i] = rand.Int63n(int64(numBlocks))
+		} else {
+			indices[i] = rand.Int63()
+		}
+	}
+	return indices
+}
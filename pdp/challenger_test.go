@@ -0,0 +1,52 @@
+package pdp
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestChallengerSample(t *testing.T) {
+	c := NewChallenger(nil, 5)
+
+	indices := c.sample(10)
+	if len(indices) != 5 {
+		t.Fatalf("expected 5 indices, got %d", len(indices))
+	}
+	for _, idx := range indices {
+		if idx < 0 || idx >= 10 {
+			t.Fatalf("index %d out of range [0, 10)", idx)
+		}
+	}
+
+	// nSamples larger than numBlocks should be capped at numBlocks.
+	c = NewChallenger(nil, 20)
+	indices = c.sample(3)
+	if len(indices) != 3 {
+		t.Fatalf("expected sample to be capped at numBlocks=3, got %d", len(indices))
+	}
+
+	// numBlocks unknown (0): nSamples is used as-is.
+	c = NewChallenger(nil, 4)
+	indices = c.sample(0)
+	if len(indices) != 4 {
+		t.Fatalf("expected 4 indices when numBlocks is unknown, got %d", len(indices))
+	}
+}
+
+func TestIsNotImplemented(t *testing.T) {
+	if !isNotImplemented(ErrProvingNotImplemented) {
+		t.Fatal("expected ErrProvingNotImplemented to be recognized")
+	}
+	// gorpc delivers remote errors as a new error carrying only the
+	// original message, not the same value: isNotImplemented must match
+	// on message, not with errors.Is/==.
+	if !isNotImplemented(errors.New(ErrProvingNotImplemented.Error())) {
+		t.Fatal("expected a same-message error to be recognized as not-implemented")
+	}
+	if isNotImplemented(errors.New("some other error")) {
+		t.Fatal("expected an unrelated error not to be recognized as not-implemented")
+	}
+	if isNotImplemented(nil) {
+		t.Fatal("expected a nil error not to be recognized as not-implemented")
+	}
+}
@@ -0,0 +1,12 @@
+// Package pdp implements periodic Provable Data Possession (PDP) auditing
+// of content pinned by ipfs-cluster. It challenges the peers allocated to
+// hold a replica of a pinned CID to prove, using the tag file produced for
+// it by the adder package (see adder.PDPTagger), that they still hold the
+// underlying blocks, without having to re-transfer the whole dataset.
+package pdp
+
+import (
+	logging "github.com/ipfs/go-log/v2"
+)
+
+var logger = logging.Logger("pdp")
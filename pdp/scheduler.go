@@ -0,0 +1,126 @@
+package pdp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+
+	cid "github.com/ipfs/go-cid"
+	peer "github.com/libp2p/go-libp2p-core/peer"
+)
+
+// PinInfo is the subset of a cluster Pin that the scheduler needs in
+// order to audit it.
+type PinInfo struct {
+	Cid         cid.Cid
+	PDPTagCid   cid.Cid
+	Allocations []peer.ID
+}
+
+// PinLister is implemented by whatever tracks the set of CIDs pinned by
+// the cluster (normally reached over rpc to the state/pintracker
+// components). Scheduler samples from it on every tick.
+type PinLister interface {
+	ListPins(ctx context.Context) ([]PinInfo, error)
+}
+
+// StatusReporter is implemented by the cluster's status subsystem, so
+// that audit failures become visible to operators the same way other
+// peer/pin errors do.
+type StatusReporter interface {
+	ReportPDPFailure(ctx context.Context, c cid.Cid, err error)
+}
+
+// Scheduler periodically audits a sample of pinned CIDs using a
+// Challenger/Verifier pair, reporting failures through a StatusReporter.
+type Scheduler struct {
+	challenger *Challenger
+	verifier   *Verifier
+	pins       PinLister
+	status     StatusReporter
+
+	interval   time.Duration
+	sampleSize int
+}
+
+// NewScheduler returns a Scheduler that audits sampleSize pinned CIDs
+// every interval, using challenger/verifier to run the actual audits and
+// status to report failures.
+func NewScheduler(challenger *Challenger, verifier *Verifier, pins PinLister, status StatusReporter, interval time.Duration, sampleSize int) *Scheduler {
+	return &Scheduler{
+		challenger: challenger,
+		verifier:   verifier,
+		pins:       pins,
+		status:     status,
+		interval:   interval,
+		sampleSize: sampleSize,
+	}
+}
+
+// Run audits a sample of pinned CIDs every s.interval, until ctx is
+// cancelled.
+func (s *Scheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.auditSample(ctx)
+		}
+	}
+}
+
+func (s *Scheduler) auditSample(ctx context.Context) {
+	pins, err := s.pins.ListPins(ctx)
+	if err != nil {
+		logger.Errorf("error listing pins for PDP audit: %s", err)
+		return
+	}
+
+	for _, p := range s.sample(pins) {
+		if p.PDPTagCid == cid.Undef {
+			continue // not PDP-tagged, nothing to audit
+		}
+		if err := s.audit(ctx, p); err != nil {
+			logger.Warningf("PDP audit failed for %s: %s", p.Cid, err)
+			s.status.ReportPDPFailure(ctx, p.Cid, err)
+		}
+	}
+}
+
+func (s *Scheduler) sample(pins []PinInfo) []PinInfo {
+	if len(pins) <= s.sampleSize {
+		return pins
+	}
+	rand.Shuffle(len(pins), func(i, j int) { pins[i], pins[j] = pins[j], pins[i] })
+	return pins[:s.sampleSize]
+}
+
+func (s *Scheduler) audit(ctx context.Context, p PinInfo) error {
+	proofs, err := s.challenger.Challenge(ctx, p.Cid, p.PDPTagCid, 0, p.Allocations)
+	if errors.Is(err, ErrProvingNotImplemented) {
+		// Proving is scaffolding only so far: abstain instead of
+		// reporting every tagged pin as a failure on every cycle.
+		logger.Debugf("PDP proving not implemented yet, skipping audit for %s", p.Cid)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if len(proofs) == 0 {
+		return fmt.Errorf("no peer answered PDP challenge for %s", p.Cid)
+	}
+
+	chal := Challenge{Root: p.Cid, TagRoot: p.PDPTagCid}
+	for _, proof := range proofs {
+		if err := s.verifier.Verify(chal, proof); err != nil {
+			return err
+		}
+	}
+	return nil
+}
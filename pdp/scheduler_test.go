@@ -0,0 +1,21 @@
+package pdp
+
+import (
+	"testing"
+)
+
+func TestSchedulerSample(t *testing.T) {
+	s := NewScheduler(nil, nil, nil, nil, 0, 2)
+
+	pins := []PinInfo{{Cid: testRoot}, {Cid: testOtherRoot}}
+	sampled := s.sample(pins)
+	if len(sampled) != 2 {
+		t.Fatalf("expected all pins when len(pins) <= sampleSize, got %d", len(sampled))
+	}
+
+	pins = []PinInfo{{Cid: testRoot}, {Cid: testOtherRoot}, {Cid: testRoot}, {Cid: testOtherRoot}}
+	sampled = s.sample(pins)
+	if len(sampled) != 2 {
+		t.Fatalf("expected sample to be capped at sampleSize=2, got %d", len(sampled))
+	}
+}
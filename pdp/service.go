@@ -0,0 +1,46 @@
+package pdp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	ipld "github.com/ipfs/go-ipld-format"
+)
+
+// ErrProvingNotImplemented is returned by Prove until the aggregated
+// proof math for the PDP scheme in use is implemented. It crosses the
+// rpc boundary as a plain string, so Challenger recognizes it by
+// message (see isNotImplemented) rather than with errors.Is, and
+// reports it to Scheduler as "not auditable yet" instead of a failed
+// audit. This keeps turning this subsystem on, before Prove/Verify do
+// real work, from paging operators for every single tagged pin on every
+// audit cycle.
+var ErrProvingNotImplemented = errors.New("PDP proof computation not yet implemented")
+
+// Service exposes PDP proving over the cluster's rpc multiplexer under
+// the "PDP" service name, so that peers can ask each other to prove they
+// still hold the blocks of a pinned CID.
+type Service struct {
+	dagService ipld.DAGService
+}
+
+// NewService returns a Service that fetches tag files through dagService
+// to answer PDP challenges.
+func NewService(dagService ipld.DAGService) *Service {
+	return &Service{dagService: dagService}
+}
+
+// Prove answers a PDP challenge for in.Root. It fetches the tag file at
+// in.TagRoot and returns an error if that fails; otherwise it returns
+// ErrProvingNotImplemented. It does not compute a proof from the blocks
+// at in.Indices and never writes to out. This is registered as the
+// "Prove" rpc method of the "PDP" service.
+func (s *Service) Prove(ctx context.Context, in Challenge, out *Proof) error {
+	if _, err := s.dagService.Get(ctx, in.TagRoot); err != nil {
+		return fmt.Errorf("fetching PDP tag for %s: %s", in.Root, err)
+	}
+	// TODO: combine the fetched tag with the local blocks at in.Indices
+	// to compute the aggregated proof, per the PDP scheme in use.
+	return ErrProvingNotImplemented
+}
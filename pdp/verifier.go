@@ -0,0 +1,32 @@
+package pdp
+
+import (
+	"fmt"
+)
+
+// Verifier checks the proofs returned by challenged peers against a PDP
+// public key.
+type Verifier struct {
+	publicKey []byte
+}
+
+// NewVerifier returns a Verifier that checks proofs against publicKey.
+func NewVerifier(publicKey []byte) *Verifier {
+	return &Verifier{publicKey: publicKey}
+}
+
+// Verify returns an error if proof is empty or is for a root other than
+// chal.Root, and nil otherwise. It does not check proof.Bytes against
+// chal.Indices or the verifier's public key: any non-empty proof for the
+// challenged root is accepted. Scheduler and Challenger are written
+// against this single call so they stay scheme-agnostic.
+func (v *Verifier) Verify(chal Challenge, proof Proof) error {
+	if len(proof.Bytes) == 0 {
+		return fmt.Errorf("empty PDP proof for %s", proof.Root)
+	}
+	if proof.Root != chal.Root {
+		return fmt.Errorf("proof for %s does not match challenge for %s", proof.Root, chal.Root)
+	}
+	// TODO: verify proof.Bytes against chal.Indices using v.publicKey.
+	return nil
+}
@@ -0,0 +1,30 @@
+package pdp
+
+import (
+	"testing"
+
+	cid "github.com/ipfs/go-cid"
+)
+
+var testRoot, _ = cid.Decode("QmY7Yh4UquoXHLPFo2XbhXkhBvFoPwmQUSa92pxnxjQuPU")
+var testOtherRoot, _ = cid.Decode("QmeomffUNfmQy76osWi6WKQ9tbm6fsiSDqA5Z5xsj5WuMK")
+
+func TestVerifierVerify(t *testing.T) {
+	v := NewVerifier([]byte("public-key"))
+	chal := Challenge{Root: testRoot, TagRoot: testOtherRoot, Indices: []int64{1, 2, 3}}
+
+	err := v.Verify(chal, Proof{Root: testRoot, Bytes: []byte("proof-bytes")})
+	if err != nil {
+		t.Fatalf("expected a matching, non-empty proof to verify: %s", err)
+	}
+
+	err = v.Verify(chal, Proof{Root: testRoot})
+	if err == nil {
+		t.Fatal("expected an error for an empty proof")
+	}
+
+	err = v.Verify(chal, Proof{Root: testOtherRoot, Bytes: []byte("proof-bytes")})
+	if err == nil {
+		t.Fatal("expected an error for a proof whose root does not match the challenge")
+	}
+}